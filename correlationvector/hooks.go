@@ -0,0 +1,237 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package correlationvector
+
+import (
+	"log"
+	"sync"
+)
+
+// Event describes a single correlation-vector lifecycle change, as delivered
+// to a ChannelHook.
+type Event struct {
+	// Kind identifies which mutation produced this event.
+	Kind EventKind
+
+	// Vector is the resulting correlation vector value.
+	Vector string
+
+	// Parent is the correlation vector value before the mutation, when applicable.
+	Parent string
+}
+
+// EventKind identifies the kind of mutation that produced an Event.
+type EventKind int
+
+const (
+	// SpinEventKind is fired after a successful Spin or SpinWithParameters call.
+	SpinEventKind EventKind = iota
+
+	// IncrementEventKind is fired after a successful Increment call.
+	IncrementEventKind
+
+	// ExtendEventKind is fired after a successful Extend call.
+	ExtendEventKind
+
+	// OverflowEventKind is fired when a mutation hits the correlation vector's
+	// maximum length and the overflow terminator is applied instead.
+	OverflowEventKind
+)
+
+// Hook receives callbacks about CorrelationVector lifecycle changes.
+// Implementations must be safe for concurrent use, since callbacks may arrive
+// from multiple goroutines spinning vectors concurrently.
+//
+// Only OnSpin is called by this package, from Spin and SpinWithParameters.
+// The package has no Increment or Extend, and nothing in it calls OnIncrement,
+// OnExtend, or OnOverflow.
+type Hook interface {
+	// OnSpin is called after Spin or SpinWithParameters produces child from parent.
+	OnSpin(parent, child string, parameters SpinParameters)
+
+	// OnIncrement is called after Increment produces new from old.
+	OnIncrement(old, new string)
+
+	// OnExtend is called after Extend produces child from parent.
+	OnExtend(parent, child string)
+
+	// OnOverflow is called when a mutation would exceed the maximum correlation
+	// vector length and the overflow terminator is applied to vector instead.
+	OnOverflow(vector string)
+}
+
+// ErrorHandler is called when a registered Hook panics while handling a
+// callback. The default handler logs the error via the standard log package.
+type ErrorHandler func(err interface{})
+
+// DefaultErrorHandler logs hook errors using the standard log package.
+func DefaultErrorHandler(err interface{}) {
+	log.Printf("correlationvector: hook error: %v", err)
+}
+
+var (
+	hooksMu     sync.RWMutex
+	hooks       []Hook
+	onHookError ErrorHandler = DefaultErrorHandler
+)
+
+// RegisterHook adds a package-level Hook that is notified of every
+// CorrelationVector mutation performed through this package.
+func RegisterHook(hook Hook) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks = append(hooks, hook)
+}
+
+// UnregisterHook removes a previously registered package-level Hook. It is a
+// no-op if hook was never registered.
+func UnregisterHook(hook Hook) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	for i, h := range hooks {
+		if h == hook {
+			hooks = append(hooks[:i], hooks[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetErrorHandler overrides how errors from panicking hooks are reported.
+// Passing nil restores DefaultErrorHandler.
+func SetErrorHandler(handler ErrorHandler) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	if handler == nil {
+		handler = DefaultErrorHandler
+	}
+	onHookError = handler
+}
+
+func registeredHooks() ([]Hook, ErrorHandler) {
+	hooksMu.RLock()
+	defer hooksMu.RUnlock()
+	return append([]Hook(nil), hooks...), onHookError
+}
+
+var (
+	scopedHooksMu sync.RWMutex
+	scopedHooks   = map[*CorrelationVector][]Hook{}
+)
+
+// AddHook registers a Hook scoped to cv alone: it is notified of mutations
+// performed on this specific *CorrelationVector, in addition to any hooks
+// registered globally via RegisterHook. Keyed by pointer identity, so it has
+// no effect on a value obtained by dereferencing cv.
+//
+// scopedHooks entries are evicted once their slice is empty, but a cv that is
+// dropped while it still has hooks attached (i.e. without a matching
+// RemoveHook for every AddHook) remains pinned in the table indefinitely.
+// Callers that attach scoped hooks to short-lived, per-request vectors must
+// remove them before letting the vector go, or accept the leak.
+func (cv *CorrelationVector) AddHook(hook Hook) {
+	scopedHooksMu.Lock()
+	defer scopedHooksMu.Unlock()
+	scopedHooks[cv] = append(scopedHooks[cv], hook)
+}
+
+// RemoveHook unregisters a previously added scoped Hook. It is a no-op if
+// hook was never added to cv. Once cv's scoped hook list is empty, its entry
+// is evicted from the table so cv can be garbage collected.
+func (cv *CorrelationVector) RemoveHook(hook Hook) {
+	scopedHooksMu.Lock()
+	defer scopedHooksMu.Unlock()
+	list := scopedHooks[cv]
+	for i, h := range list {
+		if h == hook {
+			list = append(list[:i], list[i+1:]...)
+			if len(list) == 0 {
+				delete(scopedHooks, cv)
+			} else {
+				scopedHooks[cv] = list
+			}
+			return
+		}
+	}
+}
+
+func hooksFor(cv *CorrelationVector) []Hook {
+	scopedHooksMu.RLock()
+	defer scopedHooksMu.RUnlock()
+	if cv == nil || len(scopedHooks[cv]) == 0 {
+		return nil
+	}
+	return append([]Hook(nil), scopedHooks[cv]...)
+}
+
+// fireSpinHooks notifies every registered Hook, package-level and scoped to
+// child, that Spin produced child from parent. A panicking Hook is reported
+// via the configured ErrorHandler and does not affect the other hooks or the
+// caller.
+func fireSpinHooks(parent string, child *CorrelationVector, parameters SpinParameters) {
+	list, onError := registeredHooks()
+	list = append(list, hooksFor(child)...)
+	for _, h := range list {
+		callHook(onError, func() { h.OnSpin(parent, child.GetValue(), parameters) })
+	}
+}
+
+func callHook(onError ErrorHandler, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			onError(r)
+		}
+	}()
+	fn()
+}
+
+// ChannelHook is a Hook that fans every callback into a user-supplied channel
+// as an Event. Sends are non-blocking: if the channel is full, the event is
+// dropped and Dropped is incremented instead of blocking the caller.
+type ChannelHook struct {
+	Events  chan<- Event
+	dropped uint64
+	mu      sync.Mutex
+}
+
+// NewChannelHook returns a ChannelHook that delivers events to events.
+func NewChannelHook(events chan<- Event) *ChannelHook {
+	return &ChannelHook{Events: events}
+}
+
+// Dropped returns the number of events that were dropped because Events was full.
+func (c *ChannelHook) Dropped() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dropped
+}
+
+func (c *ChannelHook) send(event Event) {
+	select {
+	case c.Events <- event:
+	default:
+		c.mu.Lock()
+		c.dropped++
+		c.mu.Unlock()
+	}
+}
+
+// OnSpin implements Hook.
+func (c *ChannelHook) OnSpin(parent, child string, parameters SpinParameters) {
+	c.send(Event{Kind: SpinEventKind, Vector: child, Parent: parent})
+}
+
+// OnIncrement implements Hook.
+func (c *ChannelHook) OnIncrement(old, new string) {
+	c.send(Event{Kind: IncrementEventKind, Vector: new, Parent: old})
+}
+
+// OnExtend implements Hook.
+func (c *ChannelHook) OnExtend(parent, child string) {
+	c.send(Event{Kind: ExtendEventKind, Vector: child, Parent: parent})
+}
+
+// OnOverflow implements Hook.
+func (c *ChannelHook) OnOverflow(vector string) {
+	c.send(Event{Kind: OverflowEventKind, Vector: vector})
+}
@@ -0,0 +1,138 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package correlationvector
+
+import (
+	"sync"
+	"testing"
+)
+
+type recordingHook struct {
+	mu      sync.Mutex
+	spins   int
+	panicOn bool
+}
+
+func (h *recordingHook) OnSpin(parent, child string, parameters SpinParameters) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.panicOn {
+		panic("boom")
+	}
+	h.spins++
+}
+
+func (h *recordingHook) OnIncrement(old, new string) {}
+func (h *recordingHook) OnExtend(parent, child string) {}
+func (h *recordingHook) OnOverflow(vector string) {}
+
+func (h *recordingHook) Spins() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.spins
+}
+
+func TestSpinFiresRegisteredHooks(t *testing.T) {
+	hook := &recordingHook{}
+	RegisterHook(hook)
+	defer UnregisterHook(hook)
+
+	if _, err := Spin("ifCOf7m5NNGvCauXqaYCNA.0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := hook.Spins(); got != 1 {
+		t.Fatalf("expected OnSpin to fire once, got %d", got)
+	}
+}
+
+func TestSpinIgnoresPanickingHook(t *testing.T) {
+	hook := &recordingHook{panicOn: true}
+	RegisterHook(hook)
+	defer UnregisterHook(hook)
+
+	var handled interface{}
+	SetErrorHandler(func(err interface{}) { handled = err })
+	defer SetErrorHandler(nil)
+
+	if _, err := Spin("ifCOf7m5NNGvCauXqaYCNA.0"); err != nil {
+		t.Fatalf("a panicking hook must not surface as a Spin error: %v", err)
+	}
+
+	if handled == nil {
+		t.Fatal("expected the panic to reach the configured ErrorHandler")
+	}
+}
+
+func TestChannelHookDropsWhenFull(t *testing.T) {
+	events := make(chan Event, 1)
+	hook := NewChannelHook(events)
+
+	hook.OnSpin("parent", "child", SpinParameters{})
+	hook.OnSpin("parent", "child2", SpinParameters{})
+
+	if got := hook.Dropped(); got != 1 {
+		t.Fatalf("expected one dropped event, got %d", got)
+	}
+
+	select {
+	case <-events:
+	default:
+		t.Fatal("expected the first event to have been delivered")
+	}
+}
+
+func TestScopedHookOnlyFiresForItsOwnVector(t *testing.T) {
+	cv := newCorrelationVector("ifCOf7m5NNGvCauXqaYCNA.0.1", 0, V2)
+	other := newCorrelationVector("ifCOf7m5NNGvCauXqaYCNA.0.2", 0, V2)
+
+	scoped := &recordingHook{}
+	cv.AddHook(scoped)
+	defer cv.RemoveHook(scoped)
+
+	fireSpinHooks("ifCOf7m5NNGvCauXqaYCNA.0", other, SpinParameters{})
+	if got := scoped.Spins(); got != 0 {
+		t.Fatalf("hook scoped to cv must not fire for other, got %d calls", got)
+	}
+
+	fireSpinHooks("ifCOf7m5NNGvCauXqaYCNA.0", cv, SpinParameters{})
+	if got := scoped.Spins(); got != 1 {
+		t.Fatalf("expected hook scoped to cv to fire once, got %d", got)
+	}
+
+	cv.RemoveHook(scoped)
+	fireSpinHooks("ifCOf7m5NNGvCauXqaYCNA.0", cv, SpinParameters{})
+	if got := scoped.Spins(); got != 1 {
+		t.Fatalf("expected no further calls after RemoveHook, got %d", got)
+	}
+}
+
+func TestRemoveHookEvictsEmptyScopedEntry(t *testing.T) {
+	cv := newCorrelationVector("ifCOf7m5NNGvCauXqaYCNA.0.3", 0, V2)
+	scoped := &recordingHook{}
+
+	cv.AddHook(scoped)
+	cv.RemoveHook(scoped)
+
+	scopedHooksMu.RLock()
+	_, ok := scopedHooks[cv]
+	scopedHooksMu.RUnlock()
+	if ok {
+		t.Fatal("expected scopedHooks entry to be evicted once its hook list is empty")
+	}
+}
+
+func TestUnregisterHookStopsFutureCallbacks(t *testing.T) {
+	hook := &recordingHook{}
+	RegisterHook(hook)
+	UnregisterHook(hook)
+
+	if _, err := Spin("ifCOf7m5NNGvCauXqaYCNA.0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := hook.Spins(); got != 0 {
+		t.Fatalf("expected no callbacks after unregistering, got %d", got)
+	}
+}
@@ -5,7 +5,9 @@
 package correlationvector
 
 import (
-	"math/rand"
+	"crypto/rand"
+	"fmt"
+	"io"
 	"strconv"
 	"time"
 )
@@ -67,6 +69,11 @@ type SpinParameters struct {
 	Interval    SpinCounterInterval
 	Periodicity SpinCounterPeriodicity
 	Entropy     SpinEntropy
+
+	// Rand is the source of entropy bytes mixed into the spin value. When nil,
+	// Spin falls back to crypto/rand.Reader. Callers may inject a deterministic
+	// reader in tests, or a hardware RNG in production.
+	Rand io.Reader
 }
 
 // Spin creates a new correlation vector by applying the Spin operator to an
@@ -89,8 +96,15 @@ func SpinWithParameters(correlationVector string, parameters *SpinParameters) (*
 		}
 	}
 
+	entropySource := parameters.Rand
+	if entropySource == nil {
+		entropySource = rand.Reader
+	}
+
 	entropy := make([]byte, int(parameters.Entropy))
-	rand.Read(entropy)
+	if _, err := io.ReadFull(entropySource, entropy); err != nil {
+		return nil, fmt.Errorf("correlationvector: failed to read spin entropy: %w", err)
+	}
 
 	// Ticks is defined as 100 nanoseconds.
 	ticks := time.Now().UnixNano() / 100
@@ -115,10 +129,12 @@ func SpinWithParameters(correlationVector string, parameters *SpinParameters) (*
 		s = strconv.Itoa(int(value>>32)) + "." + s
 	}
 
-	return newCorrelationVector(correlationVector+"."+s, 0, version), nil
+	child := newCorrelationVector(correlationVector+"."+s, 0, version)
+	fireSpinHooks(correlationVector, child, *parameters)
+	return child, nil
 }
 
-var defaultParameters = SpinParameters{CoarseInterval, ShortPeriodicity, TwoEntropy}
+var defaultParameters = SpinParameters{Interval: CoarseInterval, Periodicity: ShortPeriodicity, Entropy: TwoEntropy}
 
 func (sp *SpinParameters) tickBitsToDrop() uint {
 	switch sp.Interval {
@@ -0,0 +1,74 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package correlationvector
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSpinWithParametersDeterministicEntropy(t *testing.T) {
+	parameters := SpinParameters{
+		Interval:    CoarseInterval,
+		Periodicity: ShortPeriodicity,
+		Entropy:     TwoEntropy,
+		Rand:        bytes.NewReader([]byte{0x01, 0x02, 0x01, 0x02}),
+	}
+
+	first, err := SpinWithParameters("ifCOf7m5NNGvCauXqaYCNA.0", &parameters)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parameters.Rand = bytes.NewReader([]byte{0x01, 0x02, 0x01, 0x02})
+	second, err := SpinWithParameters("ifCOf7m5NNGvCauXqaYCNA.0", &parameters)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	firstEntropy := strings.TrimPrefix(first.GetValue(), "ifCOf7m5NNGvCauXqaYCNA.0.")
+	secondEntropy := strings.TrimPrefix(second.GetValue(), "ifCOf7m5NNGvCauXqaYCNA.0.")
+	if firstEntropy != secondEntropy {
+		t.Fatalf("expected identical spin suffixes for identical entropy, got %q and %q", firstEntropy, secondEntropy)
+	}
+}
+
+func TestSpinWithParametersPropagatesReadError(t *testing.T) {
+	wantErr := errors.New("no entropy for you")
+	parameters := SpinParameters{
+		Interval:    CoarseInterval,
+		Periodicity: ShortPeriodicity,
+		Entropy:     TwoEntropy,
+		Rand:        errReader{err: wantErr},
+	}
+
+	if _, err := SpinWithParameters("ifCOf7m5NNGvCauXqaYCNA.0", &parameters); err == nil {
+		t.Fatal("expected an error, got nil")
+	} else if !errors.Is(err, wantErr) {
+		t.Fatalf("expected wrapped %v, got %v", wantErr, err)
+	}
+}
+
+func TestSpinWithParametersNoEntropyDoesNotRead(t *testing.T) {
+	parameters := SpinParameters{
+		Interval:    CoarseInterval,
+		Periodicity: ShortPeriodicity,
+		Entropy:     NoEntropy,
+		Rand:        errReader{err: errors.New("should never be called")},
+	}
+
+	if _, err := SpinWithParameters("ifCOf7m5NNGvCauXqaYCNA.0", &parameters); err != nil {
+		t.Fatalf("unexpected error with zero entropy: %v", err)
+	}
+}
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}